@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/MisustinIvan/time_tracker/pkg/tracker"
+)
+
+func print_by_tag(t *tracker.Tracker, args ...string) error {
+	totals, err := t.ByTag(args...)
+	if err != nil {
+		return err
+	}
+
+	for _, tt := range totals {
+		fmt.Printf("%s: %s (%.2f Kč)\n", tt.Name, tt.Duration.String(), tt.Money)
+	}
+
+	return nil
+}
+
+func print_status(t *tracker.Tracker) error {
+	session, err := t.Status()
+	if err != nil {
+		return err
+	}
+	if session == nil {
+		fmt.Printf("No session is currently running\n")
+		return nil
+	}
+
+	elapsed := time.Since(session.StartedAt)
+	fmt.Printf("Running: %q, started %s (%s elapsed)\n", session.Description, session.StartedAt, elapsed.String())
+	return nil
+}
+
+func run_export(t *tracker.Tracker, args ...string) error {
+	if len(args) != 1 && len(args) != 3 {
+		return fmt.Errorf("Invalid number of arguments!")
+	}
+
+	format := args[0]
+
+	var month, year int64
+	has_range := false
+	if len(args) == 3 {
+		var err error
+		month, err = strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return err
+		}
+		year, err = strconv.ParseInt(args[2], 10, 64)
+		if err != nil {
+			return err
+		}
+		has_range = true
+	}
+
+	switch format {
+	case "csv":
+		return t.ExportCSV(os.Stdout, month, year, has_range)
+	case "json":
+		return t.ExportJSON(os.Stdout, month, year, has_range)
+	default:
+		return fmt.Errorf("Unknown export format: %s", format)
+	}
+}
+
+func run_import(t *tracker.Tracker, args ...string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("Invalid number of arguments!")
+	}
+
+	format := args[0]
+	path := args[1]
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return t.Import(format, file)
+}
@@ -1,181 +1,12 @@
 package main
 
 import (
-	"database/sql"
 	"fmt"
 	"os"
-	"strconv"
-	"strings"
-	"time"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/MisustinIvan/time_tracker/pkg/tracker"
 )
 
-var db_dir string
-var db *sql.DB
-
-func initialize() error {
-	err := os.MkdirAll(db_dir, os.ModePerm)
-	if err != nil {
-		return err
-	}
-
-	const query = `
-create table time_entries (
-id integer primary key autoincrement,
-start datetime,
-duration time,
-description text
-);
-create table tax (
-	rate real
-);
-insert into tax (rate) values (0);
-create table wage (
-	rate real
-);
-insert into wage (rate) values (0);
-`
-
-	_, err = db.Exec(query)
-	return err
-}
-
-func set_tax(args ...string) (float64, error) {
-	if len(args) != 1 {
-		return 0, fmt.Errorf("Invalid amount of arguments")
-	}
-
-	rate, err := strconv.ParseFloat(args[0], 64)
-	if err != nil {
-		return 0, fmt.Errorf("Failed to parse argument as number: %e\n", err)
-	}
-
-	query := "update tax set rate = ?"
-
-	if _, err := db.Exec(query, rate); err != nil {
-		return 0, err
-	}
-
-	return rate, nil
-}
-
-func set_wage(args ...string) (float64, error) {
-	if len(args) != 1 {
-		return 0, fmt.Errorf("Invalid amount of arugments")
-	}
-
-	rate, err := strconv.ParseFloat(args[0], 64)
-	if err != nil {
-		return 0, fmt.Errorf("Failed to parse argument as number: %e\n", err)
-	}
-
-	query := "update wage set rate = ?"
-
-	if _, err := db.Exec(query, rate); err != nil {
-		return 0, err
-	}
-
-	return rate, nil
-}
-
-func add(args ...string) error {
-	darg := args[0]
-	var unit time.Duration
-	switch darg[len(darg)-1] {
-	case 'h':
-		unit = time.Hour
-	case 'm':
-		unit = time.Minute
-	case 's':
-		unit = time.Second
-	default:
-		return fmt.Errorf("Duration with invalid unit: %s", darg)
-	}
-
-	n, err := strconv.ParseFloat(darg[:len(darg)-1], 64)
-	if err != nil {
-		return err
-	}
-
-	duration := time.Duration(float64(unit) * n)
-	start_time := time.Now().Add(-duration)
-
-	query := "insert into time_entries (duration, start, description) values (?,?,?)"
-	description := strings.Join(args[1:], " ")
-	_, err = db.Exec(query, duration, start_time, description)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func total(args ...string) (time.Duration, error) {
-	if len(args) != 2 {
-		return 0, fmt.Errorf("Invalid number of arguments!")
-	}
-
-	month_s := args[0]
-	month_i, err := strconv.ParseInt(month_s, 10, 64)
-	if err != nil {
-		return 0, err
-	}
-
-	year_s := args[1]
-	year_i, err := strconv.ParseInt(year_s, 10, 64)
-	if err != nil {
-		return 0, err
-	}
-
-	start := time.Date(int(year_i), time.Month(month_i), 0, 0, 0, 0, 0, time.UTC)
-	end := start.AddDate(0, 1, 0)
-
-	query := "select coalesce(sum(duration), 0) from time_entries where start > ? and start < ?"
-	var total time.Duration
-	if err := db.QueryRow(query, start, end).Scan(&total); err != nil {
-		return 0, err
-	}
-
-	return total, nil
-}
-
-func total_money(args ...string) (float64, error) {
-	if len(args) != 2 {
-		return 0, fmt.Errorf("Invalid number of arguments!")
-	}
-
-	month_s := args[0]
-	month_i, err := strconv.ParseInt(month_s, 10, 64)
-	if err != nil {
-		return 0, err
-	}
-
-	year_s := args[1]
-	year_i, err := strconv.ParseInt(year_s, 10, 64)
-	if err != nil {
-		return 0, err
-	}
-
-	start := time.Date(int(year_i), time.Month(month_i), 0, 0, 0, 0, 0, time.UTC)
-	end := start.AddDate(0, 1, 0)
-
-	query := `
-    select
-        (coalesce(sum(duration), 0) / 3600000000000.0)
-        * coalesce((select rate from wage limit 1), 0)
-        * (1 - coalesce((select rate from tax limit 1), 0))
-    from time_entries
-    where start > ? and start < ?
-`
-	var money float64
-	if err := db.QueryRow(query, start, end).Scan(&money); err != nil {
-		return 0, err
-	}
-
-	return money, nil
-}
-
 func print_usage() {
 	const usage = `
 time_tracker - a tool for tracking time spent on various activities
@@ -183,40 +14,53 @@ time_tracker - a tool for tracking time spent on various activities
 usage: time_tracker {command} {arguments...}
 
 commands:
-	- add: {duration} {description}
-	- total: {month} {year}
-	- total_money: {month} {year}
-	- set_tax: {rate}
-	- set_wage: {rate}
+	- add: {duration} {description} [@tag ...]
+	- total: {month} {year} [--tag {tag}]
+	- total_money: {month} {year} [--tag {tag}]
+	- by_tag: {month} {year}
+	- export: {csv|json} [month] [year]
+	- import: {csv|json} {file}
+	- start: {description} [--force]
+	- stop
+	- status
+	- set_tax: {rate} [tag]
+	- set_wage: {rate} [tag]
 	- init
+	- tui: browse, edit and delete entries interactively
 `
 	fmt.Printf(usage)
 }
 
-func setup() error {
+func db_path() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	const db_location_home string = ".config/time_tracker/"
+	dir := home + "/" + db_location_home
 
-	db_dir = home + "/" + db_location_home
-
-	db, err = sql.Open("sqlite3", db_dir+"/"+"db.db")
-	return err
-}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", err
+	}
 
-func cleanup() {
-	db.Close()
+	return dir + "/" + "db.db", nil
 }
 
 func main() {
-	if err := setup(); err != nil {
+	path, err := db_path()
+	if err != nil {
 		fmt.Printf("Could not setup program: %s\n", err)
 		os.Exit(-1)
 	}
 
+	t, err := tracker.New(path)
+	if err != nil {
+		fmt.Printf("Could not setup program: %s\n", err)
+		os.Exit(-1)
+	}
+	defer t.Close()
+
 	if len(os.Args) < 2 {
 		print_usage()
 		os.Exit(-1)
@@ -224,31 +68,65 @@ func main() {
 
 	switch os.Args[1] {
 	case "add":
-		if err := add(os.Args[2:]...); err != nil {
+		if err := t.Add(os.Args[2:]...); err != nil {
 			fmt.Printf("Could not add a new entry: %s\n", err)
 		} else {
 			fmt.Printf("Successfully added entry\n")
 		}
 	case "total":
-		if time, err := total(os.Args[2:]...); err != nil {
+		if total, err := t.Total(os.Args[2:]...); err != nil {
 			fmt.Printf("Could not get total: %s\n", err)
 		} else {
-			fmt.Printf("Total: %s", time.String())
+			fmt.Printf("Total: %s", total.String())
 		}
 	case "total_money":
-		if money, err := total_money(os.Args[2:]...); err != nil {
+		if money, err := t.TotalMoney(os.Args[2:]...); err != nil {
 			fmt.Printf("Could not get total: %s\n", err)
 		} else {
 			fmt.Printf("Total: %f Kč", money)
 		}
+	case "by_tag":
+		if err := print_by_tag(t, os.Args[2:]...); err != nil {
+			fmt.Printf("Could not get breakdown by tag: %s\n", err)
+		}
+	case "export":
+		if err := run_export(t, os.Args[2:]...); err != nil {
+			fmt.Printf("Could not export entries: %s\n", err)
+		}
+	case "import":
+		if err := run_import(t, os.Args[2:]...); err != nil {
+			fmt.Printf("Could not import entries: %s\n", err)
+		} else {
+			fmt.Printf("Successfully imported entries\n")
+		}
+	case "start":
+		if err := t.Start(os.Args[2:]...); err != nil {
+			fmt.Printf("Could not start a session: %s\n", err)
+		} else {
+			fmt.Printf("Session started\n")
+		}
+	case "stop":
+		if err := t.Stop(); err != nil {
+			fmt.Printf("Could not stop the session: %s\n", err)
+		} else {
+			fmt.Printf("Session stopped\n")
+		}
+	case "status":
+		if err := print_status(t); err != nil {
+			fmt.Printf("Could not get session status: %s\n", err)
+		}
+	case "tui":
+		if err := run_tui(t); err != nil {
+			fmt.Printf("tui exited with an error: %s\n", err)
+		}
 	case "init":
-		if err := initialize(); err != nil {
+		if err := t.Initialize(); err != nil {
 			fmt.Printf("Could not initialize database: %s\n", err)
 		} else {
 			fmt.Printf("Successfully initialized database\n")
 		}
 	case "set_tax":
-		if rate, err := set_tax(os.Args[2:]...); err != nil {
+		if rate, err := t.SetTax(os.Args[2:]...); err != nil {
 			fmt.Printf("Failed to set tax rate: %e\n", err)
 			return
 		} else {
@@ -256,7 +134,7 @@ func main() {
 			return
 		}
 	case "set_wage":
-		if rate, err := set_wage(os.Args[2:]...); err != nil {
+		if rate, err := t.SetWage(os.Args[2:]...); err != nil {
 			fmt.Printf("Failed to set tax rate: %e\n", err)
 			return
 		} else {
@@ -266,6 +144,4 @@ func main() {
 	default:
 		print_usage()
 	}
-
-	cleanup()
 }
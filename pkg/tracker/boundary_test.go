@@ -0,0 +1,58 @@
+package tracker
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMonthBoundaryConsistency guards against Total/TotalMoney/ByTag and
+// ListEntries/ExportCSV disagreeing about which month an entry belongs
+// to. All of them must treat an entry as belonging to the month it
+// actually starts in.
+func TestMonthBoundaryConsistency(t *testing.T) {
+	tr := new_test_tracker(t)
+
+	last_day_of_june := time.Date(2026, time.June, 30, 23, 0, 0, 0, time.UTC)
+	if _, err := tr.db.Exec(
+		"insert into time_entries (start, duration, description) values (?, ?, ?)",
+		last_day_of_june, time.Hour, "june entry",
+	); err != nil {
+		t.Fatalf("seeding entry: %v", err)
+	}
+
+	first_day_of_july := time.Date(2026, time.July, 1, 1, 0, 0, 0, time.UTC)
+	if _, err := tr.db.Exec(
+		"insert into time_entries (start, duration, description) values (?, ?, ?)",
+		first_day_of_july, time.Hour, "july entry",
+	); err != nil {
+		t.Fatalf("seeding entry: %v", err)
+	}
+
+	july_total, err := tr.Total("7", "2026")
+	if err != nil {
+		t.Fatalf("Total: %v", err)
+	}
+	if july_total != time.Hour {
+		t.Errorf("Total(7, 2026) = %s, want 1h (only the july entry)", july_total)
+	}
+
+	july_entries, err := tr.ListEntries(7, 2026, "")
+	if err != nil {
+		t.Fatalf("ListEntries: %v", err)
+	}
+	if len(july_entries) != 1 || july_entries[0].Description != "july entry" {
+		t.Fatalf("ListEntries(7, 2026) = %+v, want only the july entry", july_entries)
+	}
+
+	var buf strings.Builder
+	if err := tr.ExportCSV(&buf, 7, 2026, true); err != nil {
+		t.Fatalf("ExportCSV: %v", err)
+	}
+	if strings.Contains(buf.String(), "june entry") {
+		t.Errorf("ExportCSV(7, 2026) leaked the june entry:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "july entry") {
+		t.Errorf("ExportCSV(7, 2026) is missing the july entry:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,68 @@
+package tracker
+
+import "time"
+
+// Entry is a single time_entries row, identified by id so it can be
+// edited or deleted in place.
+type Entry struct {
+	ID          int64
+	Start       time.Time
+	Duration    time.Duration
+	Description string
+}
+
+// ListEntries fetches the entries for the given month/year, restricted
+// to those whose description contains filter.
+func (t *Tracker) ListEntries(month, year int, filter string) ([]Entry, error) {
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	query := `
+select id, start, duration, description
+from time_entries
+where start >= ? and start < ? and description like ?
+order by start asc
+`
+	rows, err := t.db.Query(query, start, end, "%"+filter+"%")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []Entry
+	for rows.Next() {
+		var e Entry
+		if err := rows.Scan(&e.ID, &e.Start, &e.Duration, &e.Description); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func (t *Tracker) DeleteEntry(id int64) error {
+	_, err := t.db.Exec("delete from time_entries where id = ?", id)
+	return err
+}
+
+func (t *Tracker) UpdateEntry(id int64, start time.Time, duration time.Duration, description string) error {
+	_, err := t.db.Exec(
+		"update time_entries set start = ?, duration = ?, description = ? where id = ?",
+		start, duration, description, id,
+	)
+	return err
+}
+
+// WageRate returns the default (non-tag-specific) wage rate.
+func (t *Tracker) WageRate() (float64, error) {
+	var rate float64
+	err := t.db.QueryRow("select rate from wage where tag_id is null").Scan(&rate)
+	return rate, err
+}
+
+// TaxRate returns the default (non-tag-specific) tax rate.
+func (t *Tracker) TaxRate() (float64, error) {
+	var rate float64
+	err := t.db.QueryRow("select rate from tax where tag_id is null").Scan(&rate)
+	return rate, err
+}
@@ -0,0 +1,12 @@
+package tracker
+
+import "errors"
+
+var (
+	// ErrInvalidArgs is returned by the command methods when they're
+	// called with the wrong number (or shape) of arguments.
+	ErrInvalidArgs = errors.New("invalid arguments")
+	// ErrInvalidUnit is returned by Add when a duration is suffixed
+	// with something other than h, m or s.
+	ErrInvalidUnit = errors.New("duration with invalid unit")
+)
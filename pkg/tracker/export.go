@@ -0,0 +1,228 @@
+package tracker
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportEntry is the on-disk shape used by both the csv and json
+// exporters/importers: columns start,duration_seconds,description,tags.
+type ExportEntry struct {
+	Start           time.Time `json:"start"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	Description     string    `json:"description"`
+	Tags            []string  `json:"tags"`
+}
+
+// CollectExportEntries loads entries (optionally restricted to a
+// month/year) together with their tags, ordered by start time.
+func (t *Tracker) CollectExportEntries(month, year int64, has_range bool) ([]ExportEntry, error) {
+	var (
+		rows *sql.Rows
+		err  error
+	)
+
+	if has_range {
+		start := time.Date(int(year), time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+		end := start.AddDate(0, 1, 0)
+		rows, err = t.db.Query("select id, start, duration, description from time_entries where start >= ? and start < ? order by start asc", start, end)
+	} else {
+		rows, err = t.db.Query("select id, start, duration, description from time_entries order by start asc")
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []ExportEntry
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		var e ExportEntry
+		var duration time.Duration
+		if err := rows.Scan(&id, &e.Start, &duration, &e.Description); err != nil {
+			return nil, err
+		}
+		e.DurationSeconds = duration.Seconds()
+		ids = append(ids, id)
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, id := range ids {
+		tag_rows, err := t.db.Query("select t.name from tags t join entry_tags et on et.tag_id = t.id where et.entry_id = ? order by t.name", id)
+		if err != nil {
+			return nil, err
+		}
+
+		for tag_rows.Next() {
+			var name string
+			if err := tag_rows.Scan(&name); err != nil {
+				tag_rows.Close()
+				return nil, err
+			}
+			entries[i].Tags = append(entries[i].Tags, name)
+		}
+		err = tag_rows.Err()
+		tag_rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return entries, nil
+}
+
+// ExportCSV writes entries (optionally restricted to month/year) to w in
+// the start,duration_seconds,description,tags format.
+func (t *Tracker) ExportCSV(w io.Writer, month, year int64, has_range bool) error {
+	entries, err := t.CollectExportEntries(month, year, has_range)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"start", "duration_seconds", "description", "tags"}); err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		record := []string{
+			e.Start.Format(time.RFC3339Nano),
+			strconv.FormatFloat(e.DurationSeconds, 'f', -1, 64),
+			e.Description,
+			strings.Join(e.Tags, ","),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	return writer.Error()
+}
+
+// ExportJSON writes entries (optionally restricted to month/year) to w
+// as a JSON array of ExportEntry.
+func (t *Tracker) ExportJSON(w io.Writer, month, year int64, has_range bool) error {
+	entries, err := t.CollectExportEntries(month, year, has_range)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(entries)
+}
+
+// Import reads entries in the given format (csv or json) from r,
+// inserting them while deduplicating against existing rows by
+// (start, duration, description).
+func (t *Tracker) Import(format string, r io.Reader) error {
+	var entries []ExportEntry
+	var err error
+	switch format {
+	case "csv":
+		entries, err = parse_csv(r)
+	case "json":
+		entries, err = parse_json(r)
+	default:
+		return fmt.Errorf("unknown import format: %s", format)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		duration := time.Duration(e.DurationSeconds * float64(time.Second))
+
+		var exists int
+		query := "select count(*) from time_entries where start = ? and duration = ? and description = ?"
+		if err := t.db.QueryRow(query, e.Start, duration, e.Description).Scan(&exists); err != nil {
+			return err
+		}
+		if exists > 0 {
+			continue
+		}
+
+		res, err := t.db.Exec("insert into time_entries (start, duration, description) values (?, ?, ?)", e.Start, duration, e.Description)
+		if err != nil {
+			return err
+		}
+
+		entry_id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		for _, name := range e.Tags {
+			tag_id, err := t.get_or_create_tag(name)
+			if err != nil {
+				return err
+			}
+			if _, err := t.db.Exec("insert or ignore into entry_tags (entry_id, tag_id) values (?, ?)", entry_id, tag_id); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func parse_csv(r io.Reader) ([]ExportEntry, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	var entries []ExportEntry
+	for _, record := range records[1:] {
+		if len(record) != 4 {
+			return nil, fmt.Errorf("malformed csv record: %v", record)
+		}
+
+		start, err := time.Parse(time.RFC3339, record[0])
+		if err != nil {
+			return nil, err
+		}
+
+		seconds, err := strconv.ParseFloat(record[1], 64)
+		if err != nil {
+			return nil, err
+		}
+
+		var tags []string
+		if record[3] != "" {
+			tags = strings.Split(record[3], ",")
+		}
+
+		entries = append(entries, ExportEntry{
+			Start:           start,
+			DurationSeconds: seconds,
+			Description:     record[2],
+			Tags:            tags,
+		})
+	}
+
+	return entries, nil
+}
+
+func parse_json(r io.Reader) ([]ExportEntry, error) {
+	var entries []ExportEntry
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,162 @@
+package tracker
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// migration describes a single, ordered step that brings the schema from
+// one version to the next. Migrations are never edited after release;
+// fixes ship as new migrations so everyone's database converges the
+// same way regardless of which version they started from.
+type migration struct {
+	name  string
+	apply func(*sql.Tx) error
+}
+
+var migrations = []migration{
+	{name: "create_schema", apply: migrate_create_schema},
+	{name: "toutc", apply: migrate_toutc},
+}
+
+func migrate_create_schema(tx *sql.Tx) error {
+	const query = `
+create table time_entries (
+id integer primary key autoincrement,
+start datetime,
+duration time,
+description text
+);
+create table tags (
+	id integer primary key autoincrement,
+	name text unique
+);
+create table entry_tags (
+	entry_id integer references time_entries(id),
+	tag_id integer references tags(id),
+	primary key (entry_id, tag_id)
+);
+create table sessions (
+	id integer primary key autoincrement,
+	started_at datetime,
+	description text
+);
+create table tax (
+	tag_id integer references tags(id),
+	rate real
+);
+insert into tax (tag_id, rate) values (null, 0);
+create table wage (
+	tag_id integer references tags(id),
+	rate real
+);
+insert into wage (tag_id, rate) values (null, 0);
+`
+	_, err := tx.Exec(query)
+	return err
+}
+
+// migrate_toutc normalizes existing time_entries.start values to UTC, so
+// later date-range arithmetic in Total/TotalMoney doesn't depend on the
+// timezone an entry happened to be inserted in.
+func migrate_toutc(tx *sql.Tx) error {
+	rows, err := tx.Query("select id, start from time_entries")
+	if err != nil {
+		return err
+	}
+
+	type entry_start struct {
+		id    int64
+		start time.Time
+	}
+	var entries []entry_start
+	for rows.Next() {
+		var e entry_start
+		if err := rows.Scan(&e.id, &e.start); err != nil {
+			rows.Close()
+			return err
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	for _, e := range entries {
+		if _, err := tx.Exec("update time_entries set start = ? where id = ?", e.start.UTC(), e.id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// legacy_schema_version is the schema_version a database created before
+// the migration runner existed (by the old initialize(), or by main.go
+// before chunk0-4) should start at: migrate_create_schema has already
+// run in substance, so only migrations after it still need to apply.
+const legacy_schema_version = 1
+
+// has_legacy_schema reports whether time_entries already exists, which
+// is only possible on a database that predates schema_version tracking.
+func (t *Tracker) has_legacy_schema() (bool, error) {
+	var name string
+	err := t.db.QueryRow("select name from sqlite_master where type = 'table' and name = 'time_entries'").Scan(&name)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Initialize brings the schema up to date with the compiled-in
+// migration list, applying whatever hasn't run yet, each in its own
+// transaction, and recording progress in schema_version as it goes.
+func (t *Tracker) Initialize() error {
+	if _, err := t.db.Exec("create table if not exists schema_version (version integer)"); err != nil {
+		return err
+	}
+
+	var version int
+	err := t.db.QueryRow("select version from schema_version limit 1").Scan(&version)
+	if err == sql.ErrNoRows {
+		version = 0
+		if legacy, err := t.has_legacy_schema(); err != nil {
+			return err
+		} else if legacy {
+			version = legacy_schema_version
+		}
+		if _, err := t.db.Exec("insert into schema_version (version) values (?)", version); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := t.db.Begin()
+		if err != nil {
+			return err
+		}
+
+		if err := migrations[i].apply(tx); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %q failed: %w", migrations[i].name, err)
+		}
+
+		if _, err := tx.Exec("update schema_version set version = ?", i+1); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package tracker
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// legacy_schema_ddl is the schema exactly as migrate_create_schema
+// creates it, standing in for a database created before the migration
+// runner existed (no schema_version table, but the tables already in
+// place).
+const legacy_schema_ddl = `
+create table time_entries (
+id integer primary key autoincrement,
+start datetime,
+duration time,
+description text
+);
+create table tags (
+	id integer primary key autoincrement,
+	name text unique
+);
+create table entry_tags (
+	entry_id integer references time_entries(id),
+	tag_id integer references tags(id),
+	primary key (entry_id, tag_id)
+);
+create table sessions (
+	id integer primary key autoincrement,
+	started_at datetime,
+	description text
+);
+create table tax (
+	tag_id integer references tags(id),
+	rate real
+);
+insert into tax (tag_id, rate) values (null, 0);
+create table wage (
+	tag_id integer references tags(id),
+	rate real
+);
+insert into wage (tag_id, rate) values (null, 0);
+`
+
+// TestInitializeUpgradesLegacyDatabase verifies that a database created
+// before the migration runner existed can still be opened: Initialize
+// must not replay migrate_create_schema against tables that already
+// exist, and must still run later migrations (toutc) against the
+// pre-existing data.
+func TestInitializeUpgradesLegacyDatabase(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(legacy_schema_ddl); err != nil {
+		t.Fatalf("seeding legacy schema: %v", err)
+	}
+
+	non_utc_start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+	if _, err := db.Exec(
+		"insert into time_entries (start, duration, description) values (?, ?, ?)",
+		non_utc_start, time.Hour, "legacy entry",
+	); err != nil {
+		t.Fatalf("seeding legacy entry: %v", err)
+	}
+
+	tr := &Tracker{db: db}
+	if err := tr.Initialize(); err != nil {
+		t.Fatalf("Initialize on a pre-migration database should succeed, got: %v", err)
+	}
+
+	var version int
+	if err := db.QueryRow("select version from schema_version limit 1").Scan(&version); err != nil {
+		t.Fatalf("reading schema_version: %v", err)
+	}
+	if version != len(migrations) {
+		t.Errorf("schema_version = %d, want %d", version, len(migrations))
+	}
+
+	entries, err := tr.ListEntries(1, 2026, "")
+	if err != nil {
+		t.Fatalf("ListEntries: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Description != "legacy entry" {
+		t.Fatalf("ListEntries = %+v, want the pre-existing legacy entry to survive", entries)
+	}
+	if entries[0].Start.Location() != time.UTC {
+		t.Errorf("legacy entry start location = %v, want UTC (toutc migration should still run)", entries[0].Start.Location())
+	}
+
+	// Initialize must also be idempotent: running it again on an
+	// already-migrated database should be a no-op, not an error.
+	if err := tr.Initialize(); err != nil {
+		t.Fatalf("second Initialize should be a no-op, got: %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package tracker
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RunningSession is the currently open sessions row, if any.
+type RunningSession struct {
+	ID          int64
+	StartedAt   time.Time
+	Description string
+}
+
+// Status returns the currently running session, or nil if none is open.
+func (t *Tracker) Status() (*RunningSession, error) {
+	var s RunningSession
+	err := t.db.QueryRow("select id, started_at, description from sessions limit 1").
+		Scan(&s.ID, &s.StartedAt, &s.Description)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Start opens a new running session. If one is already open, it refuses
+// unless --force is given, in which case the old session is discarded.
+func (t *Tracker) Start(args ...string) error {
+	force := false
+	var description_words []string
+	for _, a := range args {
+		if a == "--force" {
+			force = true
+		} else {
+			description_words = append(description_words, a)
+		}
+	}
+
+	existing, err := t.Status()
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		if !force {
+			return fmt.Errorf("a session is already running: %q (started %s)", existing.Description, existing.StartedAt)
+		}
+		if _, err := t.db.Exec("delete from sessions where id = ?", existing.ID); err != nil {
+			return err
+		}
+	}
+
+	description := strings.Join(description_words, " ")
+	_, err = t.db.Exec("insert into sessions (started_at, description) values (?, ?)", time.Now(), description)
+	return err
+}
+
+// Stop closes the currently running session, if any, and materializes
+// it as a time_entries row.
+func (t *Tracker) Stop() error {
+	session, err := t.Status()
+	if err != nil {
+		return err
+	}
+	if session == nil {
+		return fmt.Errorf("no session is currently running")
+	}
+
+	duration := time.Since(session.StartedAt)
+
+	query := "insert into time_entries (duration, start, description) values (?,?,?)"
+	if _, err := t.db.Exec(query, duration, session.StartedAt, session.Description); err != nil {
+		return err
+	}
+
+	_, err = t.db.Exec("delete from sessions where id = ?", session.ID)
+	return err
+}
@@ -0,0 +1,83 @@
+package tracker
+
+import (
+	"strconv"
+	"time"
+)
+
+// get_or_create_tag returns the id of the tag with the given name,
+// creating it first if it doesn't exist yet.
+func (t *Tracker) get_or_create_tag(name string) (int64, error) {
+	var id int64
+	err := t.db.QueryRow("select id from tags where name = ?", name).Scan(&id)
+	if err == nil {
+		return id, nil
+	}
+
+	res, err := t.db.Exec("insert into tags (name) values (?)", name)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.LastInsertId()
+}
+
+// TagTotal is one row of a ByTag breakdown.
+type TagTotal struct {
+	Name     string
+	Duration time.Duration
+	Money    float64
+}
+
+// ByTag reports, for the given month and year, how much time and money
+// was billed under each tag that appears on at least one entry.
+func (t *Tracker) ByTag(args ...string) ([]TagTotal, error) {
+	if len(args) != 2 {
+		return nil, ErrInvalidArgs
+	}
+
+	month_i, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	year_i, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Date(int(year_i), time.Month(month_i), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	query := `
+    select
+        t.name,
+        coalesce(sum(te.duration), 0) as total_duration,
+        (coalesce(sum(te.duration), 0) / 3600000000000.0)
+            * coalesce((select rate from wage where tag_id = t.id), (select rate from wage where tag_id is null))
+            * (1 - coalesce((select rate from tax where tag_id = t.id), (select rate from tax where tag_id is null)))
+    from tags t
+    join entry_tags et on et.tag_id = t.id
+    join time_entries te on te.id = et.entry_id
+    where te.start >= ? and te.start < ?
+    group by t.id
+    order by t.name
+`
+
+	rows, err := t.db.Query(query, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []TagTotal
+	for rows.Next() {
+		var tt TagTotal
+		if err := rows.Scan(&tt.Name, &tt.Duration, &tt.Money); err != nil {
+			return nil, err
+		}
+		totals = append(totals, tt)
+	}
+
+	return totals, rows.Err()
+}
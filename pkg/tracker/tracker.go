@@ -0,0 +1,294 @@
+// Package tracker implements the time_tracker domain logic (entries,
+// tags, sessions, migrations, export/import) behind a Tracker type, so
+// it can be driven by something other than the CLI in main - a tui, an
+// http server, or a test.
+package tracker
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Tracker owns a single sqlite connection and exposes the time_tracker
+// domain logic independently of any particular CLI or UI.
+type Tracker struct {
+	db *sql.DB
+}
+
+// New opens the sqlite database at db_path and brings its schema up to
+// date. Pass ":memory:" for an ephemeral, test-only database.
+func New(db_path string) (*Tracker, error) {
+	db, err := sql.Open("sqlite3", db_path)
+	if err != nil {
+		return nil, err
+	}
+	// sqlite3 only tolerates one writer at a time, and an in-memory
+	// database is scoped to a single connection - keeping the pool at
+	// one connection keeps both cases correct.
+	db.SetMaxOpenConns(1)
+
+	t := &Tracker{db: db}
+	if err := t.Initialize(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Close releases the underlying database connection.
+func (t *Tracker) Close() error {
+	return t.db.Close()
+}
+
+// set_rate updates the rate in table (wage or tax), either the global
+// default (tag == "") or the per-tag override, creating the override
+// row if this tag has not been billed at a custom rate before.
+func (t *Tracker) set_rate(table string, rate float64, tag string) error {
+	if tag == "" {
+		_, err := t.db.Exec(fmt.Sprintf("update %s set rate = ? where tag_id is null", table), rate)
+		return err
+	}
+
+	tag_id, err := t.get_or_create_tag(tag)
+	if err != nil {
+		return err
+	}
+
+	res, err := t.db.Exec(fmt.Sprintf("update %s set rate = ? where tag_id = ?", table), rate, tag_id)
+	if err != nil {
+		return err
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		_, err := t.db.Exec(fmt.Sprintf("insert into %s (tag_id, rate) values (?, ?)", table), tag_id, rate)
+		return err
+	}
+
+	return nil
+}
+
+func (t *Tracker) SetTax(args ...string) (float64, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return 0, ErrInvalidArgs
+	}
+
+	rate, err := strconv.ParseFloat(args[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse argument as number: %w", err)
+	}
+
+	var tag string
+	if len(args) == 2 {
+		tag = args[1]
+	}
+
+	if err := t.set_rate("tax", rate, tag); err != nil {
+		return 0, err
+	}
+
+	return rate, nil
+}
+
+func (t *Tracker) SetWage(args ...string) (float64, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return 0, ErrInvalidArgs
+	}
+
+	rate, err := strconv.ParseFloat(args[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse argument as number: %w", err)
+	}
+
+	var tag string
+	if len(args) == 2 {
+		tag = args[1]
+	}
+
+	if err := t.set_rate("wage", rate, tag); err != nil {
+		return 0, err
+	}
+
+	return rate, nil
+}
+
+func (t *Tracker) Add(args ...string) error {
+	if len(args) < 1 {
+		return ErrInvalidArgs
+	}
+
+	darg := args[0]
+	var unit time.Duration
+	switch darg[len(darg)-1] {
+	case 'h':
+		unit = time.Hour
+	case 'm':
+		unit = time.Minute
+	case 's':
+		unit = time.Second
+	default:
+		return fmt.Errorf("%w: %s", ErrInvalidUnit, darg)
+	}
+
+	n, err := strconv.ParseFloat(darg[:len(darg)-1], 64)
+	if err != nil {
+		return err
+	}
+
+	duration := time.Duration(float64(unit) * n)
+	start_time := time.Now().Add(-duration)
+
+	var tag_names []string
+	var description_words []string
+	for _, a := range args[1:] {
+		if strings.HasPrefix(a, "@") && len(a) > 1 {
+			tag_names = append(tag_names, a[1:])
+		} else {
+			description_words = append(description_words, a)
+		}
+	}
+	description := strings.Join(description_words, " ")
+
+	query := "insert into time_entries (duration, start, description) values (?,?,?)"
+	res, err := t.db.Exec(query, duration, start_time, description)
+	if err != nil {
+		return err
+	}
+
+	entry_id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range tag_names {
+		tag_id, err := t.get_or_create_tag(name)
+		if err != nil {
+			return err
+		}
+		if _, err := t.db.Exec("insert into entry_tags (entry_id, tag_id) values (?, ?)", entry_id, tag_id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parse_tag_flag reads an optional trailing "--tag name" pair from args,
+// returning "" if it isn't present.
+func parse_tag_flag(args []string) (string, error) {
+	if len(args) == 0 {
+		return "", nil
+	}
+
+	if len(args) != 2 || args[0] != "--tag" {
+		return "", fmt.Errorf("%w: %s", ErrInvalidArgs, strings.Join(args, " "))
+	}
+
+	return args[1], nil
+}
+
+func (t *Tracker) Total(args ...string) (time.Duration, error) {
+	if len(args) != 2 && len(args) != 4 {
+		return 0, ErrInvalidArgs
+	}
+
+	month_i, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	year_i, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	tag, err := parse_tag_flag(args[2:])
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Date(int(year_i), time.Month(month_i), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	var total time.Duration
+	if tag == "" {
+		query := "select coalesce(sum(duration), 0) from time_entries where start >= ? and start < ?"
+		err = t.db.QueryRow(query, start, end).Scan(&total)
+	} else {
+		query := `
+    select coalesce(sum(te.duration), 0)
+    from time_entries te
+    join entry_tags et on et.entry_id = te.id
+    join tags t on t.id = et.tag_id
+    where te.start >= ? and te.start < ? and t.name = ?
+`
+		err = t.db.QueryRow(query, start, end, tag).Scan(&total)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func (t *Tracker) TotalMoney(args ...string) (float64, error) {
+	if len(args) != 2 && len(args) != 4 {
+		return 0, ErrInvalidArgs
+	}
+
+	month_i, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	year_i, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	tag, err := parse_tag_flag(args[2:])
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Date(int(year_i), time.Month(month_i), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	var money float64
+	if tag == "" {
+		query := `
+    select coalesce(sum(
+        (te.duration / 3600000000000.0)
+        * coalesce((select rate from wage where tag_id = (select et.tag_id from entry_tags et where et.entry_id = te.id order by et.tag_id limit 1)), (select rate from wage where tag_id is null), 0)
+        * (1 - coalesce((select rate from tax where tag_id = (select et.tag_id from entry_tags et where et.entry_id = te.id order by et.tag_id limit 1)), (select rate from tax where tag_id is null), 0))
+    ), 0)
+    from time_entries te
+    where te.start >= ? and te.start < ?
+`
+		err = t.db.QueryRow(query, start, end).Scan(&money)
+	} else {
+		query := `
+    select
+        (coalesce(sum(te.duration), 0) / 3600000000000.0)
+        * coalesce((select rate from wage where tag_id = (select id from tags where name = ?)), (select rate from wage where tag_id is null), 0)
+        * (1 - coalesce((select rate from tax where tag_id = (select id from tags where name = ?)), (select rate from tax where tag_id is null), 0))
+    from time_entries te
+    join entry_tags et on et.entry_id = te.id
+    join tags t on t.id = et.tag_id
+    where te.start >= ? and te.start < ? and t.name = ?
+`
+		err = t.db.QueryRow(query, tag, tag, start, end, tag).Scan(&money)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return money, nil
+}
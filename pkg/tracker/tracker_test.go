@@ -0,0 +1,236 @@
+package tracker
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func new_test_tracker(t *testing.T) *Tracker {
+	t.Helper()
+
+	tr, err := New(":memory:")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { tr.Close() })
+
+	return tr
+}
+
+func TestAddAndTotal(t *testing.T) {
+	tr := new_test_tracker(t)
+
+	if err := tr.Add("1h", "reading"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := tr.Add("30m", "more", "reading"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	now := time.Now()
+	total, err := tr.Total(strconv.Itoa(int(now.Month())), strconv.Itoa(now.Year()))
+	if err != nil {
+		t.Fatalf("Total: %v", err)
+	}
+
+	want := 90 * time.Minute
+	if total != want {
+		t.Errorf("Total = %s, want %s", total, want)
+	}
+}
+
+func TestAddInvalidUnit(t *testing.T) {
+	tr := new_test_tracker(t)
+
+	err := tr.Add("1x", "bad")
+	if err == nil {
+		t.Fatal("Add with invalid unit should fail")
+	}
+	if !errors.Is(err, ErrInvalidUnit) {
+		t.Errorf("Add error = %v, want wrapping ErrInvalidUnit", err)
+	}
+}
+
+func TestTotalMoneyWithWageAndTax(t *testing.T) {
+	tr := new_test_tracker(t)
+
+	if _, err := tr.SetWage("100"); err != nil {
+		t.Fatalf("SetWage: %v", err)
+	}
+	if _, err := tr.SetTax("0.1"); err != nil {
+		t.Fatalf("SetTax: %v", err)
+	}
+	if err := tr.Add("2h", "work"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	now := time.Now()
+	money, err := tr.TotalMoney(strconv.Itoa(int(now.Month())), strconv.Itoa(now.Year()))
+	if err != nil {
+		t.Fatalf("TotalMoney: %v", err)
+	}
+
+	want := 2 * 100 * 0.9
+	if money != want {
+		t.Errorf("TotalMoney = %f, want %f", money, want)
+	}
+}
+
+func TestTotalMoneyPerTagOverride(t *testing.T) {
+	tr := new_test_tracker(t)
+
+	if _, err := tr.SetWage("100"); err != nil {
+		t.Fatalf("SetWage: %v", err)
+	}
+	if _, err := tr.SetWage("200", "clientA"); err != nil {
+		t.Fatalf("SetWage clientA: %v", err)
+	}
+	if err := tr.Add("1h", "billable", "@clientA"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	now := time.Now()
+	month, year := strconv.Itoa(int(now.Month())), strconv.Itoa(now.Year())
+
+	money, err := tr.TotalMoney(month, year, "--tag", "clientA")
+	if err != nil {
+		t.Fatalf("TotalMoney --tag: %v", err)
+	}
+
+	if money != 200 {
+		t.Errorf("TotalMoney --tag clientA = %f, want 200", money)
+	}
+}
+
+// TestTotalMoneyMatchesByTagSum guards against the no-tag TotalMoney
+// aggregate drifting from ByTag's per-tag breakdown: billing someone for
+// everything at once must equal the sum of billing per client.
+func TestTotalMoneyMatchesByTagSum(t *testing.T) {
+	tr := new_test_tracker(t)
+
+	if _, err := tr.SetWage("100"); err != nil {
+		t.Fatalf("SetWage: %v", err)
+	}
+	if _, err := tr.SetWage("200", "clientA"); err != nil {
+		t.Fatalf("SetWage clientA: %v", err)
+	}
+	if _, err := tr.SetTax("0.1"); err != nil {
+		t.Fatalf("SetTax: %v", err)
+	}
+	if err := tr.Add("1h", "billable", "@clientA"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := tr.Add("2h", "billable", "@clientB"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	now := time.Now()
+	month, year := strconv.Itoa(int(now.Month())), strconv.Itoa(now.Year())
+
+	total, err := tr.TotalMoney(month, year)
+	if err != nil {
+		t.Fatalf("TotalMoney: %v", err)
+	}
+
+	by_tag, err := tr.ByTag(month, year)
+	if err != nil {
+		t.Fatalf("ByTag: %v", err)
+	}
+
+	var by_tag_sum float64
+	for _, tt := range by_tag {
+		by_tag_sum += tt.Money
+	}
+
+	if total != by_tag_sum {
+		t.Errorf("TotalMoney = %f, want it to match the sum of ByTag (%f)", total, by_tag_sum)
+	}
+
+	want := 180.0 + 180.0
+	if total != want {
+		t.Errorf("TotalMoney = %f, want %f", total, want)
+	}
+}
+
+func TestStartStopStatus(t *testing.T) {
+	tr := new_test_tracker(t)
+
+	if err := tr.Start("deep", "work"); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	if err := tr.Start("another"); err == nil {
+		t.Fatal("Start while a session is running should fail without --force")
+	}
+
+	session, err := tr.Status()
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if session == nil || session.Description != "deep work" {
+		t.Fatalf("Status = %+v, want a running session named %q", session, "deep work")
+	}
+
+	if err := tr.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	session, err = tr.Status()
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if session != nil {
+		t.Fatalf("Status after Stop = %+v, want nil", session)
+	}
+
+	entries, err := tr.ListEntries(int(time.Now().Month()), time.Now().Year(), "")
+	if err != nil {
+		t.Fatalf("ListEntries: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Description != "deep work" {
+		t.Fatalf("ListEntries after Stop = %+v, want one entry named %q", entries, "deep work")
+	}
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	src := new_test_tracker(t)
+	if err := src.Add("1h", "work", "@clientA"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	var buf strings.Builder
+	if err := src.ExportCSV(&buf, 0, 0, false); err != nil {
+		t.Fatalf("ExportCSV: %v", err)
+	}
+
+	dst := new_test_tracker(t)
+	if err := dst.Import("csv", strings.NewReader(buf.String())); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+
+	entries, err := dst.CollectExportEntries(0, 0, false)
+	if err != nil {
+		t.Fatalf("CollectExportEntries: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Description != "work" {
+		t.Fatalf("imported entries = %+v, want one entry named %q", entries, "work")
+	}
+	if len(entries[0].Tags) != 1 || entries[0].Tags[0] != "clientA" {
+		t.Fatalf("imported tags = %v, want [clientA]", entries[0].Tags)
+	}
+
+	// importing the same file again should not duplicate the entry.
+	if err := dst.Import("csv", strings.NewReader(buf.String())); err != nil {
+		t.Fatalf("Import (again): %v", err)
+	}
+	entries, err = dst.CollectExportEntries(0, 0, false)
+	if err != nil {
+		t.Fatalf("CollectExportEntries: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("entries after re-import = %d, want 1 (dedup by start/duration/description)", len(entries))
+	}
+}
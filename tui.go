@@ -0,0 +1,328 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/MisustinIvan/time_tracker/pkg/tracker"
+)
+
+// edit_field enumerates which column of the selected entry is being edited.
+type edit_field int
+
+const (
+	field_start edit_field = iota
+	field_duration
+	field_description
+)
+
+type tui_mode int
+
+const (
+	mode_browse tui_mode = iota
+	mode_filter
+	mode_edit
+	mode_confirm_delete
+)
+
+var (
+	style_header   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
+	style_selected = lipgloss.NewStyle().Reverse(true)
+	style_footer   = lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+	style_error    = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+)
+
+type tui_model struct {
+	t *tracker.Tracker
+
+	month, year int
+	filter      string
+	entries     []tracker.Entry
+	cursor      int
+	mode        tui_mode
+
+	edit_field edit_field
+	edit_input string
+
+	err error
+}
+
+func new_tui_model(t *tracker.Tracker) tui_model {
+	now := time.Now()
+	m := tui_model{
+		t:     t,
+		month: int(now.Month()),
+		year:  now.Year(),
+		mode:  mode_browse,
+	}
+	m.reload()
+	return m
+}
+
+func (m *tui_model) reload() {
+	entries, err := m.t.ListEntries(m.month, m.year, m.filter)
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.err = nil
+	m.entries = entries
+	if m.cursor >= len(m.entries) {
+		m.cursor = len(m.entries) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+func (m *tui_model) change_month(delta int) {
+	t := time.Date(m.year, time.Month(m.month), 1, 0, 0, 0, 0, time.UTC).AddDate(0, delta, 0)
+	m.month = int(t.Month())
+	m.year = t.Year()
+	m.cursor = 0
+	m.reload()
+}
+
+// visible_totals sums duration and applied wage/tax over the entries
+// currently shown, mirroring total() and total_money().
+func (m tui_model) visible_totals() (time.Duration, float64) {
+	var total time.Duration
+	for _, e := range m.entries {
+		total += e.Duration
+	}
+
+	wage_rate, _ := m.t.WageRate()
+	tax_rate, _ := m.t.TaxRate()
+
+	money := total.Hours() * wage_rate * (1 - tax_rate)
+	return total, money
+}
+
+func (m tui_model) Init() tea.Cmd {
+	return nil
+}
+
+func (m tui_model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key_msg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch m.mode {
+	case mode_filter:
+		return m.update_filter(key_msg)
+	case mode_edit:
+		return m.update_edit(key_msg)
+	case mode_confirm_delete:
+		return m.update_confirm_delete(key_msg)
+	default:
+		return m.update_browse(key_msg)
+	}
+}
+
+func (m tui_model) update_browse(key_msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key_msg.String() {
+	case "q", "ctrl+c", "esc":
+		return m, tea.Quit
+	case "j", "down":
+		if m.cursor < len(m.entries)-1 {
+			m.cursor++
+		}
+	case "k", "up":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "h", "left":
+		m.change_month(-1)
+	case "l", "right":
+		m.change_month(1)
+	case "/":
+		m.mode = mode_filter
+		m.edit_input = m.filter
+	case "e":
+		if len(m.entries) > 0 {
+			m.mode = mode_edit
+			m.edit_field = field_start
+			m.edit_input = m.entries[m.cursor].Start.Format(time.RFC3339)
+		}
+	case "d":
+		if len(m.entries) > 0 {
+			m.mode = mode_confirm_delete
+		}
+	}
+	return m, nil
+}
+
+func (m tui_model) update_filter(key_msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key_msg.Type {
+	case tea.KeyEnter:
+		m.filter = m.edit_input
+		m.mode = mode_browse
+		m.cursor = 0
+		m.reload()
+	case tea.KeyEsc:
+		m.mode = mode_browse
+	case tea.KeyBackspace:
+		if len(m.edit_input) > 0 {
+			m.edit_input = m.edit_input[:len(m.edit_input)-1]
+		}
+	case tea.KeyRunes:
+		m.edit_input += string(key_msg.Runes)
+	case tea.KeySpace:
+		m.edit_input += " "
+	}
+	return m, nil
+}
+
+func (m tui_model) update_confirm_delete(key_msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key_msg.String() {
+	case "y":
+		if err := m.t.DeleteEntry(m.entries[m.cursor].ID); err != nil {
+			m.err = err
+		}
+		m.mode = mode_browse
+		m.reload()
+	case "n", "esc":
+		m.mode = mode_browse
+	}
+	return m, nil
+}
+
+func (m tui_model) update_edit(key_msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key_msg.Type {
+	case tea.KeyEsc:
+		m.mode = mode_browse
+		return m, nil
+	case tea.KeyTab, tea.KeyEnter:
+		if err := m.commit_edit_field(); err != nil {
+			m.err = err
+			m.mode = mode_browse
+			return m, nil
+		}
+		if key_msg.Type == tea.KeyEnter || m.edit_field == field_description {
+			m.mode = mode_browse
+			m.reload()
+			return m, nil
+		}
+		m.edit_field++
+		m.edit_input = m.starting_value_for_field(m.edit_field)
+		return m, nil
+	case tea.KeyBackspace:
+		if len(m.edit_input) > 0 {
+			m.edit_input = m.edit_input[:len(m.edit_input)-1]
+		}
+		return m, nil
+	case tea.KeyRunes:
+		m.edit_input += string(key_msg.Runes)
+		return m, nil
+	case tea.KeySpace:
+		m.edit_input += " "
+		return m, nil
+	}
+	return m, nil
+}
+
+func (m tui_model) starting_value_for_field(f edit_field) string {
+	e := m.entries[m.cursor]
+	switch f {
+	case field_start:
+		return e.Start.Format(time.RFC3339)
+	case field_duration:
+		return e.Duration.String()
+	default:
+		return e.Description
+	}
+}
+
+// commit_edit_field parses m.edit_input for the field currently being
+// edited and writes the whole entry back to the database.
+func (m *tui_model) commit_edit_field() error {
+	e := m.entries[m.cursor]
+
+	switch m.edit_field {
+	case field_start:
+		parsed, err := time.Parse(time.RFC3339, m.edit_input)
+		if err != nil {
+			return fmt.Errorf("invalid start time: %w", err)
+		}
+		e.Start = parsed
+	case field_duration:
+		parsed, err := time.ParseDuration(m.edit_input)
+		if err != nil {
+			return fmt.Errorf("invalid duration: %w", err)
+		}
+		e.Duration = parsed
+	case field_description:
+		e.Description = m.edit_input
+	}
+
+	if err := m.t.UpdateEntry(e.ID, e.Start, e.Duration, e.Description); err != nil {
+		return err
+	}
+	m.entries[m.cursor] = e
+	return nil
+}
+
+func (m tui_model) View() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n", style_header.Render(fmt.Sprintf("time_tracker - %04d-%02d", m.year, m.month)))
+	if m.filter != "" {
+		fmt.Fprintf(&b, "filter: %q\n", m.filter)
+	}
+
+	for i, e := range m.entries {
+		line := fmt.Sprintf("%4d  %-20s  %-10s  %s", e.ID, e.Start.Format("2006-01-02 15:04"), e.Duration.String(), e.Description)
+		if i == m.cursor && m.mode == mode_browse {
+			line = style_selected.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+
+	if len(m.entries) == 0 {
+		b.WriteString("(no entries)\n")
+	}
+
+	total, money := m.visible_totals()
+	fmt.Fprintf(&b, "\n%s\n", style_footer.Render(fmt.Sprintf("total: %s  (%.2f Kč)", total.String(), money)))
+
+	switch m.mode {
+	case mode_filter:
+		fmt.Fprintf(&b, "\nfilter> %s\n", m.edit_input)
+	case mode_edit:
+		fmt.Fprintf(&b, "\nediting %s> %s  (tab/enter: next field, esc: cancel)\n", m.edit_field_name(), m.edit_input)
+	case mode_confirm_delete:
+		b.WriteString("\ndelete this entry? (y/n)\n")
+	default:
+		b.WriteString("\nj/k: move  h/l: month  /: filter  e: edit  d: delete  q: quit\n")
+	}
+
+	if m.err != nil {
+		fmt.Fprintf(&b, "\n%s\n", style_error.Render(m.err.Error()))
+	}
+
+	return b.String()
+}
+
+func (m tui_model) edit_field_name() string {
+	switch m.edit_field {
+	case field_start:
+		return "start"
+	case field_duration:
+		return "duration"
+	default:
+		return "description"
+	}
+}
+
+// run_tui launches the interactive browser over time_entries. Use h/l to
+// page between months once inside.
+func run_tui(t *tracker.Tracker) error {
+	p := tea.NewProgram(new_tui_model(t))
+	_, err := p.Run()
+	return err
+}